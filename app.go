@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"github.com/jessevdk/go-flags"
+)
+
+// CommandFunc runs a single resolved command, mirroring Commander.Execute.
+type CommandFunc func(cmd Commander, args []string) error
+
+// Middleware wraps a CommandFunc with cross-cutting behavior such as
+// logging, tracing, panic recovery, timeouts, or auth.
+type Middleware func(next CommandFunc) CommandFunc
+
+// App builds a ParseExecute-style entry point with a configurable
+// middleware chain around each command's dispatch. Unlike ParseExecute, the
+// Validator/Initer dispatch is not hardcoded: New installs it as ordinary
+// middlewares, so Use can add more around them, and a fresh App built by
+// hand (rather than via New) can replace them outright.
+type App[T any] struct {
+	app         *T
+	opts        Options
+	middlewares []Middleware
+}
+
+// New creates an App for T with the built-in Validator/Initer dispatch
+// installed as its first middlewares, matching ParseExecute's historical
+// behavior. Middlewares run in the order they end up in the chain, first
+// first: the validator/initer pair installed here wraps everything Use adds
+// afterwards, and each Use call's middlewares wrap every call after it.
+func New[T any]() *App[T] {
+	a := &App[T]{app: new(T)}
+	a.middlewares = []Middleware{validatorMiddleware, initerMiddleware(a.app)}
+	return a
+}
+
+// Use appends mws to the middleware chain, in the order given. Middlewares
+// run outermost first: the first middleware ever added (by New, or by the
+// first Use call) wraps every middleware added after it, down to the
+// command's Execute at the center.
+func (a *App[T]) Use(mws ...Middleware) *App[T] {
+	a.middlewares = append(a.middlewares, mws...)
+	return a
+}
+
+// WithOptions sets the Options used for error handling, as in
+// ParseExecuteWith.
+func (a *App[T]) WithOptions(opts Options) *App[T] {
+	a.opts = opts
+	return a
+}
+
+// Parse parses os.Args and dispatches the selected command through the
+// middleware chain, returning an error instead of exiting the process.
+func (a *App[T]) Parse() (*T, error) {
+	handler := a.opts.ErrorHandler
+	if handler == nil {
+		handler = ErrorHandlerFunc(func(err error) error { return err })
+	}
+
+	var firstErr error
+	record := func(err error) {
+		if err == nil {
+			return
+		}
+		if err = handler.HandleError(err); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	chain := func(cmd Commander, args []string) error { return cmd.Execute(args) }
+	for i := len(a.middlewares) - 1; i >= 0; i-- {
+		chain = a.middlewares[i](chain)
+	}
+
+	parser := flags.NewParser(a.app, flagsOptions)
+	registerCompletion(parser, a.app)
+	parser.CommandHandler = func(command flags.Commander, args []string) error {
+		if command == nil {
+			return nil
+		}
+		cmd, ok := command.(Commander)
+		if !ok {
+			return nil
+		}
+		if err := chain(cmd, args); err != nil {
+			record(err)
+		}
+		return nil
+	}
+
+	if _, err := parser.Parse(); err != nil {
+		record(err)
+	}
+
+	return a.app, firstErr
+}
+
+// ParseExecute parses os.Args, dispatches through the middleware chain, and
+// exits the process on error, matching the package-level ParseExecute.
+func (a *App[T]) ParseExecute() *T {
+	app, err := a.Parse()
+	handleError(err)
+	return app
+}
+
+// validatorMiddleware is the built-in Middleware form of the Validator
+// dispatch ParseExecute has always performed.
+func validatorMiddleware(next CommandFunc) CommandFunc {
+	return func(cmd Commander, args []string) error {
+		if validator, ok := cmd.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				return err
+			}
+		}
+		return next(cmd, args)
+	}
+}
+
+// initerMiddleware is the built-in Middleware form of the Initer dispatch.
+// It is bound to app once, by New, since Initer needs a live *T to fill in.
+func initerMiddleware[T any](app *T) Middleware {
+	return func(next CommandFunc) CommandFunc {
+		return func(cmd Commander, args []string) error {
+			if initer, ok := cmd.(Initer[T]); ok {
+				if err := initer.Init(app); err != nil {
+					return err
+				}
+			}
+			return next(cmd, args)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestAppUseOrdersOutermostFirst(t *testing.T) {
+	var order []string
+
+	mark := func(name string) Middleware {
+		return func(next CommandFunc) CommandFunc {
+			return func(cmd Commander, args []string) error {
+				order = append(order, "enter:"+name)
+				err := next(cmd, args)
+				order = append(order, "exit:"+name)
+				return err
+			}
+		}
+	}
+
+	var chain CommandFunc = func(cmd Commander, args []string) error { return nil }
+	middlewares := []Middleware{mark("A"), mark("B")}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		chain = middlewares[i](chain)
+	}
+
+	if err := chain(nil, nil); err != nil {
+		t.Fatalf("chain: %v", err)
+	}
+
+	want := []string{"enter:A", "enter:B", "exit:B", "exit:A"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+type appTestCmd struct {
+	validated bool
+	inited    bool
+}
+
+func (c *appTestCmd) Validate() error {
+	c.validated = true
+	return nil
+}
+
+func (c *appTestCmd) Init(app *appTestApp) error {
+	c.inited = true
+	return nil
+}
+
+func (c *appTestCmd) Execute(_ []string) error {
+	return nil
+}
+
+type appTestApp struct {
+	// A value field, not a pointer: go-flags only writes a nil pointer
+	// command field back onto the parent struct when scanning it adds
+	// further options/groups, which a bare command never does - so a
+	// pre-allocated pointer is required to observe mutations afterwards,
+	// and a value field sidesteps that entirely.
+	Run appTestCmd `command:"run"`
+}
+
+func TestAppUseWrapsBuiltins(t *testing.T) {
+	withArgs(t, "run")
+
+	var order []string
+	a := New[appTestApp]()
+	a.Use(func(next CommandFunc) CommandFunc {
+		return func(cmd Commander, args []string) error {
+			order = append(order, "before")
+			err := next(cmd, args)
+			order = append(order, "after")
+			return err
+		}
+	})
+
+	app, err := a.Parse()
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !app.Run.validated || !app.Run.inited {
+		t.Fatal("Validator/Initer built-ins did not run")
+	}
+	if len(order) != 2 || order[0] != "before" || order[1] != "after" {
+		t.Fatalf("order = %v, want [before after] (user middleware runs after the built-ins)", order)
+	}
+}
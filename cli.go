@@ -32,10 +32,44 @@ type Commander interface {
 	Execute(args []string) error
 }
 
+// ParseExecute parses os.Args into T and runs the selected command, printing
+// any error to stderr and terminating the process via os.Exit. Use
+// ParseExecuteWith if you need to keep control of the process instead.
 func ParseExecute[T any]() *T {
+	app, err := ParseExecuteWith[T](Options{})
+	handleError(err)
+	return app
+}
+
+// ParseExecuteWith parses os.Args into T and runs the selected command, the
+// same way ParseExecute does, but returns the resulting error instead of
+// exiting the process. opts.ErrorHandler, if set, runs on every error -
+// including ones from Validate/Init/Execute - and may transform or swallow it.
+func ParseExecuteWith[T any](opts Options) (*T, error) {
 	var app T
+	return parseExecute(&app, opts)
+}
 
-	parser := flags.NewParser(&app, flagsOptions)
+// parseExecute drives the parser for an already-constructed app value, so
+// that callers such as ParseExecuteWithConfig can populate app first.
+func parseExecute[T any](app *T, opts Options) (*T, error) {
+	handler := opts.ErrorHandler
+	if handler == nil {
+		handler = ErrorHandlerFunc(func(err error) error { return err })
+	}
+
+	var firstErr error
+	record := func(err error) {
+		if err == nil {
+			return
+		}
+		if err = handler.HandleError(err); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	parser := flags.NewParser(app, flagsOptions)
+	registerCompletion(parser, app)
 	parser.CommandHandler = func(command flags.Commander, args []string) error {
 		if command == nil {
 			return nil
@@ -43,33 +77,36 @@ func ParseExecute[T any]() *T {
 
 		if validator, ok := command.(Validator); ok {
 			if err := validator.Validate(); err != nil {
-				handleError(err)
+				record(err)
+				return nil
 			}
 		}
 
 		if initer, ok := command.(Initer[T]); ok {
-			if err := initer.Init(&app); err != nil {
-				handleError(err)
+			if err := initer.Init(app); err != nil {
+				record(err)
+				return nil
 			}
 		}
 
 		if err := command.Execute(args); err != nil {
-			handleError(err)
+			record(err)
 		}
 
 		return nil
 	}
 
 	if _, err := parser.Parse(); err != nil {
-		handleError(err)
+		record(err)
 	}
 
-	return &app
+	return app, firstErr
 }
 
 func Parse[T any]() *T {
 	var app T
 	parser := flags.NewParser(&app, flagsOptions)
+	registerCompletion(parser, &app)
 	if _, err := parser.Parse(); err != nil {
 		handleError(err)
 	}
@@ -94,6 +131,12 @@ func handleError(err error) {
 		// Intentionally fall through to print the flags error
 	}
 
+	var exitCoder ExitCoder
+	if errors.As(err, &exitCoder) {
+		_, _ = fmt.Fprintln(os.Stderr, exitCoder)
+		os.Exit(exitCoder.ExitCode())
+	}
+
 	_, _ = fmt.Fprintln(os.Stderr, err)
 	os.Exit(1)
 }
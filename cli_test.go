@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+var errParseExecuteTestCmd = errors.New("command failed")
+
+type parseExecuteTestCmd struct{}
+
+func (c *parseExecuteTestCmd) Execute(_ []string) error {
+	return errParseExecuteTestCmd
+}
+
+type parseExecuteTestApp struct {
+	Fail *parseExecuteTestCmd `command:"fail"`
+}
+
+func withArgs(t *testing.T, args ...string) {
+	t.Helper()
+	old := os.Args
+	os.Args = append([]string{"myapp"}, args...)
+	t.Cleanup(func() { os.Args = old })
+}
+
+func TestParseExecuteWithReturnsErrorInsteadOfExiting(t *testing.T) {
+	withArgs(t, "fail")
+
+	_, err := ParseExecuteWith[parseExecuteTestApp](Options{})
+	if !errors.Is(err, errParseExecuteTestCmd) {
+		t.Fatalf("ParseExecuteWith returned %v, want %v", err, errParseExecuteTestCmd)
+	}
+}
+
+func TestParseExecuteWithErrorHandlerCanSwallow(t *testing.T) {
+	withArgs(t, "fail")
+
+	opts := Options{
+		ErrorHandler: ErrorHandlerFunc(func(err error) error { return nil }),
+	}
+
+	_, err := ParseExecuteWith[parseExecuteTestApp](opts)
+	if err != nil {
+		t.Fatalf("ParseExecuteWith returned %v, want nil (handler swallowed it)", err)
+	}
+}
+
+type parseTestApp struct {
+	Pick completerTestCmd `command:"pick"`
+}
+
+func TestParseWiresCompletionToTheLiveApp(t *testing.T) {
+	withArgs(t, "__complete", "pick", "ap")
+
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+
+	Parse[parseTestApp]()
+
+	w.Close()
+	os.Stdout = old
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.TrimSpace(string(out)); got != "apricot" {
+		t.Fatalf("Parse __complete pick ap = %q, want %q (registerCompletion must see the live app, not a disconnected copy)", got, "apricot")
+	}
+}
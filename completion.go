@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// completionCommandName is the hidden command used to print a static
+// completion script for one of the supported shells.
+const completionCommandName = "completion"
+
+// completeCommandName is the hidden command the generated shell scripts call
+// into to resolve completion candidates at runtime.
+const completeCommandName = "__complete"
+
+var supportedShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Completer is an optional interface for commands that want to offer
+// dynamic completion candidates - file names, resource IDs, anything the
+// parser tree can't enumerate on its own - beyond the static subcommand and
+// option names resolveCompletions already provides. args is the command's
+// already-completed positional arguments; partial is the word currently
+// being typed.
+type Completer interface {
+	Complete(args []string, partial string) []string
+}
+
+type completionCommand struct {
+	Args struct {
+		Shell string `positional-arg-name:"shell" choice:"bash" choice:"zsh" choice:"fish" choice:"powershell"`
+	} `positional-args:"yes" required:"yes"`
+
+	parser *flags.Parser
+}
+
+func (c *completionCommand) Execute(_ []string) error {
+	script, err := renderCompletionScript(c.parser, c.Args.Shell)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Print(script)
+	return err
+}
+
+type completeCommand struct {
+	Args struct {
+		Words []string `positional-arg-name:"word"`
+	} `positional-args:"yes"`
+
+	parser *flags.Parser
+	app    any
+}
+
+func (c *completeCommand) Execute(_ []string) error {
+	for _, candidate := range resolveCompletions(c.parser, c.app, c.Args.Words) {
+		fmt.Println(candidate)
+	}
+	return nil
+}
+
+// registerCompletion wires the hidden `completion` and `__complete` commands
+// onto parser. app is the struct parser was built from, kept around so
+// __complete can recover a subcommand's concrete type and consult its
+// Completer implementation, if any. It is called automatically by
+// ParseExecute and Parse.
+func registerCompletion(parser *flags.Parser, app any) {
+	if cmd, err := parser.AddCommand(completionCommandName, "Generate a shell completion script", "", &completionCommand{parser: parser}); err == nil {
+		cmd.Hidden = true
+	}
+
+	if cmd, err := parser.AddCommand(completeCommandName, "Resolve completion candidates", "", &completeCommand{parser: parser, app: app}); err == nil {
+		cmd.Hidden = true
+	}
+}
+
+// resolveCompletions walks args to find the command the user is currently
+// completing, then returns the matching subcommand or option names, plus
+// whatever dynamic candidates that command's Completer contributes, if it
+// implements one.
+func resolveCompletions(parser *flags.Parser, app any, words []string) []string {
+	partial := ""
+	if len(words) > 0 {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	cmd := parser.Command
+	var path []string
+	idx := 0
+	for ; idx < len(words); idx++ {
+		word := words[idx]
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		next := cmd.Find(word)
+		if next == nil {
+			break
+		}
+		cmd = next
+		path = append(path, word)
+	}
+
+	var candidates []string
+	if strings.HasPrefix(partial, "-") {
+		candidates = optionCandidates(cmd, partial)
+	} else {
+		candidates = commandCandidates(cmd, partial)
+		if completer, ok := dataAt(app, path).(Completer); ok {
+			for _, c := range completer.Complete(words[idx:], partial) {
+				if strings.HasPrefix(c, partial) {
+					candidates = append(candidates, c)
+				}
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	return candidates
+}
+
+// dataAt locates the struct go-flags registered as the subcommand at path -
+// found by walking app's own `command:"name"`-tagged fields in lockstep
+// with path, the same tag go-flags itself reads to build the command tree -
+// and returns it.
+//
+// go-flags does not expose the data a *flags.Command was registered with
+// once it is part of the parser tree, so this walks the original struct
+// directly rather than asking the parser for it.
+func dataAt(app any, path []string) any {
+	v := reflect.ValueOf(app)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	for _, name := range path {
+		next, ok := findCommandField(v, name)
+		if !ok {
+			return nil
+		}
+		v = next
+	}
+
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
+}
+
+// findCommandField looks, among v's fields, for the one tagged
+// `command:"name"` and returns the struct it points to.
+func findCommandField(v reflect.Value, name string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("command") != name {
+			continue
+		}
+
+		field := v.Field(i)
+		for field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				return reflect.Value{}, false
+			}
+			field = field.Elem()
+		}
+		return field, true
+	}
+	return reflect.Value{}, false
+}
+
+func commandCandidates(cmd *flags.Command, partial string) []string {
+	var out []string
+	for _, sub := range cmd.Commands() {
+		if sub.Hidden {
+			continue
+		}
+		if strings.HasPrefix(sub.Name, partial) {
+			out = append(out, sub.Name)
+		}
+	}
+	return out
+}
+
+func optionCandidates(cmd *flags.Command, partial string) []string {
+	var out []string
+	for _, opt := range allOptions(cmd) {
+		if long := "--" + opt.LongName; opt.LongName != "" && strings.HasPrefix(long, partial) {
+			out = append(out, long)
+		}
+		if short := "-" + string(opt.ShortName); opt.ShortName != 0 && strings.HasPrefix(short, partial) {
+			out = append(out, short)
+		}
+	}
+	return out
+}
+
+// allOptions returns every option belonging to cmd, including ones declared
+// in nested groups (e.g. the "Application Options" group go-flags creates
+// for a command's own top-level fields). cmd.Options() alone only sees
+// options added directly to cmd's own group, not its subgroups.
+func allOptions(cmd *flags.Command) []*flags.Option {
+	var out []*flags.Option
+	var walk func(g *flags.Group)
+	walk = func(g *flags.Group) {
+		out = append(out, g.Options()...)
+		for _, sub := range g.Groups() {
+			walk(sub)
+		}
+	}
+	walk(cmd.Group)
+	return out
+}
+
+// renderCompletionScript emits a static completion script for shell that
+// delegates candidate resolution to the program's own `__complete` command.
+func renderCompletionScript(parser *flags.Parser, shell string) (string, error) {
+	prog := progBase(os.Args[0])
+
+	var tmpl string
+	switch shell {
+	case "bash":
+		tmpl = bashCompletionTemplate
+	case "zsh":
+		tmpl = zshCompletionTemplate
+	case "fish":
+		tmpl = fishCompletionTemplate
+	case "powershell":
+		tmpl = powershellCompletionTemplate
+	default:
+		return "", fmt.Errorf("cli: unsupported completion shell %q, want one of %v", shell, supportedShells)
+	}
+
+	return strings.ReplaceAll(tmpl, "{{PROG}}", prog), nil
+}
+
+// progBase returns the base name of a program path, e.g. "/usr/bin/foo" ->
+// "foo".
+func progBase(p string) string {
+	if i := strings.LastIndexByte(p, '/'); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
+
+const bashCompletionTemplate = `_{{PROG}}_complete() {
+  local words cur
+  words=("${COMP_WORDS[@]:1:COMP_CWORD}")
+  COMPREPLY=($({{PROG}} __complete "${words[@]}"))
+}
+complete -F _{{PROG}}_complete {{PROG}}
+`
+
+const zshCompletionTemplate = `#compdef {{PROG}}
+_{{PROG}}_complete() {
+  local -a candidates
+  candidates=("${(@f)$({{PROG}} __complete "${words[@]:1}")}")
+  compadd -a candidates
+}
+compdef _{{PROG}}_complete {{PROG}}
+`
+
+const fishCompletionTemplate = `function __{{PROG}}_complete
+    {{PROG}} __complete (commandline -opc) (commandline -ct)
+end
+complete -c {{PROG}} -f -a '(__{{PROG}}_complete)'
+`
+
+const powershellCompletionTemplate = `Register-ArgumentCompleter -Native -CommandName {{PROG}} -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    & {{PROG}} __complete $commandAst.CommandElements[1..$commandAst.CommandElements.Count] | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jessevdk/go-flags"
+)
+
+func TestRenderCompletionScript(t *testing.T) {
+	oldArgs := os.Args
+	os.Args = []string{"/usr/local/bin/myapp"}
+	defer func() { os.Args = oldArgs }()
+
+	for _, shell := range supportedShells {
+		t.Run(shell, func(t *testing.T) {
+			script, err := renderCompletionScript(nil, shell)
+			if err != nil {
+				t.Fatalf("renderCompletionScript(%q): %v", shell, err)
+			}
+
+			if strings.Contains(script, "%!") {
+				t.Fatalf("renderCompletionScript(%q) left an unformatted verb in the output:\n%s", shell, script)
+			}
+			if strings.Contains(script, "{{PROG}}") {
+				t.Fatalf("renderCompletionScript(%q) left a placeholder unreplaced:\n%s", shell, script)
+			}
+			if !strings.Contains(script, "myapp") {
+				t.Fatalf("renderCompletionScript(%q) = %q, want it to mention the program name", shell, script)
+			}
+			if !strings.Contains(script, "__complete") {
+				t.Fatalf("renderCompletionScript(%q) = %q, want it to call the __complete command", shell, script)
+			}
+		})
+	}
+}
+
+func TestRenderCompletionScriptUnsupportedShell(t *testing.T) {
+	if _, err := renderCompletionScript(nil, "csh"); err == nil {
+		t.Fatal("renderCompletionScript(csh): want an error, got nil")
+	}
+}
+
+func TestProgBase(t *testing.T) {
+	cases := map[string]string{
+		"/usr/local/bin/myapp": "myapp",
+		"myapp":                "myapp",
+		"./myapp":              "myapp",
+	}
+	for in, want := range cases {
+		if got := progBase(in); got != want {
+			t.Errorf("progBase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+type completionTestApp struct {
+	Verbose bool `long:"verbose" short:"v"`
+	Serve   struct {
+		Port int `long:"port"`
+	} `command:"serve"`
+}
+
+func TestResolveCompletionsCommandsAndOptions(t *testing.T) {
+	var app completionTestApp
+	parser := flags.NewParser(&app, flags.Default)
+
+	commands := resolveCompletions(parser, &app, []string{"s"})
+	if len(commands) != 1 || commands[0] != "serve" {
+		t.Fatalf("resolveCompletions([]{\"s\"}) = %v, want [serve]", commands)
+	}
+
+	opts := resolveCompletions(parser, &app, []string{"--verb"})
+	if len(opts) != 1 || opts[0] != "--verbose" {
+		t.Fatalf("resolveCompletions([]{\"--verb\"}) = %v, want [--verbose]", opts)
+	}
+}
+
+type completerTestCmd struct {
+	Name string `long:"name"`
+}
+
+func (c *completerTestCmd) Execute(_ []string) error { return nil }
+
+func (c *completerTestCmd) Complete(_ []string, partial string) []string {
+	var out []string
+	for _, candidate := range []string{"alpha", "apricot", "banana"} {
+		if strings.HasPrefix(candidate, partial) {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+type completerTestApp struct {
+	Pick completerTestCmd `command:"pick"`
+}
+
+func TestResolveCompletionsDynamicCompleter(t *testing.T) {
+	var app completerTestApp
+	parser := flags.NewParser(&app, flags.Default)
+
+	candidates := resolveCompletions(parser, &app, []string{"pick", "ap"})
+	if len(candidates) != 1 || candidates[0] != "apricot" {
+		t.Fatalf("resolveCompletions(pick, ap) = %v, want [apricot]", candidates)
+	}
+}
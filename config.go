@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigLoader populates app from an external source before flags are
+// parsed, so that config values act as defaults that command-line flags and
+// go-flags' own env: tags can still override.
+type ConfigLoader[T any] interface {
+	Load(app *T) error
+}
+
+// ConfigLoaderFunc adapts a plain function to ConfigLoader.
+type ConfigLoaderFunc[T any] func(app *T) error
+
+func (f ConfigLoaderFunc[T]) Load(app *T) error {
+	return f(app)
+}
+
+// Reloader is an optional interface for commands that want to receive
+// SIGHUP-triggered config reloads while Execute is running.
+type Reloader interface {
+	Reload() error
+}
+
+type configFlags struct {
+	Config string `long:"config" description:"Path to a config file (.json, .yaml, .yml, or .toml)"`
+}
+
+// ParseExecuteWithConfig works like ParseExecuteWith, but first runs loader
+// against a zero-value T so config values become the defaults that flags,
+// in turn, override. A --config flag is registered automatically; when
+// present, LoadConfigFile(path) is used in place of loader for that run.
+func ParseExecuteWithConfig[T any](loader ConfigLoader[T], opts Options) (*T, error) {
+	var app T
+
+	if path := extractConfigFlag(os.Args[1:]); path != "" {
+		loader = LoadConfigFile[T](path)
+	}
+
+	if loader != nil {
+		if err := loader.Load(&app); err != nil {
+			return &app, fmt.Errorf("cli: loading config: %w", err)
+		}
+	}
+
+	return parseExecuteWithConfigFlag(&app, opts)
+}
+
+// parseExecuteWithConfigFlag is parseExecute plus the --config group (for
+// discoverability in --help) and SIGHUP-triggered Reloader dispatch.
+func parseExecuteWithConfigFlag[T any](app *T, opts Options) (*T, error) {
+	holder := &configFlags{}
+
+	handler := opts.ErrorHandler
+	if handler == nil {
+		handler = ErrorHandlerFunc(func(err error) error { return err })
+	}
+
+	var firstErr error
+	record := func(err error) {
+		if err == nil {
+			return
+		}
+		if err = handler.HandleError(err); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	parser := flags.NewParser(app, flagsOptions)
+	registerCompletion(parser, app)
+	if _, err := parser.AddGroup("Config", "Config file options", holder); err != nil {
+		record(err)
+	}
+
+	// go-flags unconditionally resets every option that wasn't set on the
+	// command line back to its own default: tag (or env: var) once parsing
+	// finishes, which would otherwise stomp whatever the config loader just
+	// wrote into app. Promoting the loaded value to the option's own Default
+	// lets env vars and flags keep overriding it exactly as they already do.
+	applyConfigDefaults(parser, reflect.ValueOf(app).Elem())
+
+	parser.CommandHandler = func(command flags.Commander, args []string) error {
+		if command == nil {
+			return nil
+		}
+
+		if validator, ok := command.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				record(err)
+				return nil
+			}
+		}
+
+		if initer, ok := command.(Initer[T]); ok {
+			if err := initer.Init(app); err != nil {
+				record(err)
+				return nil
+			}
+		}
+
+		if reloader, ok := command.(Reloader); ok {
+			watchReload(reloader)
+		}
+
+		if err := command.Execute(args); err != nil {
+			record(err)
+		}
+
+		return nil
+	}
+
+	if _, err := parser.Parse(); err != nil {
+		record(err)
+	}
+
+	return app, firstErr
+}
+
+// watchReload re-invokes reloader.Reload on every SIGHUP for the lifetime of
+// the process; callers running a long-lived command (a server, a watcher)
+// get config-reload-without-restart semantics for free.
+func watchReload(reloader Reloader) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	go func() {
+		for range sig {
+			_ = reloader.Reload()
+		}
+	}()
+}
+
+// extractConfigFlag scans args for --config <path> or --config=<path> ahead
+// of the real flags.Parse pass, so the config file can be loaded before any
+// flag defaults are established.
+func extractConfigFlag(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--config" && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--config="):
+			return strings.TrimPrefix(arg, "--config=")
+		}
+	}
+	return ""
+}
+
+// LoadConfigFile builds a ConfigLoader that reads path - a JSON, YAML, or
+// TOML file selected by its extension - into a generic document, then
+// assigns its values onto app's fields tagged with `config:"section.key"`.
+func LoadConfigFile[T any](path string) ConfigLoader[T] {
+	return ConfigLoaderFunc[T](func(app *T) error {
+		doc, err := decodeConfigFile(path)
+		if err != nil {
+			return err
+		}
+		return assignConfigTags(reflect.ValueOf(app).Elem(), doc)
+	})
+}
+
+func decodeConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &doc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("cli: unrecognized config file extension %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cli: parsing %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// assignConfigTags walks v's fields, resolving each `config:"section.key"`
+// tag as a dot-separated path into doc and assigning the result.
+func assignConfigTags(v reflect.Value, doc map[string]any) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("config")
+		if tag == "" {
+			continue
+		}
+
+		value, ok := lookupConfigPath(doc, strings.Split(tag, "."))
+		if !ok {
+			continue
+		}
+
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("cli: config %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// applyConfigDefaults walks v's config:-tagged fields and, for the ones that
+// also carry a long: or short: go-flags tag and were actually populated by
+// the config loader (i.e. are no longer their zero value), makes that the
+// flag's own Default. That way flags.Parser.Parse's env-var-then-default
+// fallback still runs, but lands on the config value instead of the struct's
+// default: tag - preserving defaults -> config -> env -> flags precedence.
+func applyConfigDefaults(parser *flags.Parser, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("config") == "" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+
+		if opt := findOptionByTag(parser, field); opt != nil {
+			opt.Default = defaultLiteral(fv)
+		}
+	}
+}
+
+func findOptionByTag(parser *flags.Parser, field reflect.StructField) *flags.Option {
+	if long := field.Tag.Get("long"); long != "" {
+		if opt := parser.Command.FindOptionByLongName(long); opt != nil {
+			return opt
+		}
+	}
+	if short := field.Tag.Get("short"); short != "" {
+		if r, size := utf8.DecodeRuneInString(short); size > 0 {
+			return parser.Command.FindOptionByShortName(r)
+		}
+	}
+	return nil
+}
+
+func defaultLiteral(field reflect.Value) []string {
+	if field.Kind() == reflect.Slice {
+		out := make([]string, field.Len())
+		for i := range out {
+			out[i] = fmt.Sprint(field.Index(i).Interface())
+		}
+		return out
+	}
+	return []string{fmt.Sprint(field.Interface())}
+}
+
+func lookupConfigPath(doc map[string]any, path []string) (any, bool) {
+	var cur any = doc
+	for _, key := range path {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func setField(field reflect.Value, value any) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(fmt.Sprint(value))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(fmt.Sprint(value))
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected a list, got %T", value)
+		}
+		out := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(out.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(out)
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+	return nil
+}
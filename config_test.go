@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractConfigFlag(t *testing.T) {
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"serve", "--config", "app.yaml"}, "app.yaml"},
+		{[]string{"serve", "--config=app.yaml"}, "app.yaml"},
+		{[]string{"serve"}, ""},
+		{[]string{"--config"}, ""},
+	}
+
+	for _, c := range cases {
+		if got := extractConfigFlag(c.args); got != c.want {
+			t.Errorf("extractConfigFlag(%v) = %q, want %q", c.args, got, c.want)
+		}
+	}
+}
+
+type configTestApp struct {
+	Host string `config:"server.host"`
+	Port int    `config:"server.port"`
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	content := `{"server": {"host": "localhost", "port": 8080}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var app configTestApp
+	if err := LoadConfigFile[configTestApp](path).Load(&app); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if app.Host != "localhost" {
+		t.Errorf("Host = %q, want localhost", app.Host)
+	}
+	if app.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", app.Port)
+	}
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	content := "server:\n  host: localhost\n  port: 9090\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var app configTestApp
+	if err := LoadConfigFile[configTestApp](path).Load(&app); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if app.Port != 9090 {
+		t.Errorf("Port = %d, want 9090", app.Port)
+	}
+}
+
+func TestLoadConfigFileUnrecognizedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.ini")
+	if err := os.WriteFile(path, []byte("nope"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var app configTestApp
+	if err := LoadConfigFile[configTestApp](path).Load(&app); err == nil {
+		t.Fatal("Load: want an error for an unrecognized extension, got nil")
+	}
+}
+
+type precedenceTestCmd struct{}
+
+func (c *precedenceTestCmd) Execute(_ []string) error { return nil }
+
+// precedenceTestApp's Port is both a go-flags option (long+default) and
+// config-bound, the combination the precedence bug only showed up for.
+type precedenceTestApp struct {
+	Port int               `long:"port" default:"1234" config:"server.port" env:"CLI_TEST_PORT"`
+	Run  precedenceTestCmd `command:"run"`
+}
+
+func writeConfigFile(t *testing.T, port int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.json")
+	content := fmt.Sprintf(`{"server": {"port": %d}}`, port)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseExecuteWithConfigFlagBeatsConfigAndDefault(t *testing.T) {
+	withArgs(t, "--config", writeConfigFile(t, 9090), "--port", "7777", "run")
+
+	app, err := ParseExecuteWithConfig[precedenceTestApp](nil, Options{})
+	if err != nil {
+		t.Fatalf("ParseExecuteWithConfig: %v", err)
+	}
+	if app.Port != 7777 {
+		t.Errorf("Port = %d, want 7777 (command-line flag must win)", app.Port)
+	}
+}
+
+func TestParseExecuteWithConfigEnvBeatsConfig(t *testing.T) {
+	withArgs(t, "--config", writeConfigFile(t, 9090), "run")
+	t.Setenv("CLI_TEST_PORT", "5555")
+
+	app, err := ParseExecuteWithConfig[precedenceTestApp](nil, Options{})
+	if err != nil {
+		t.Fatalf("ParseExecuteWithConfig: %v", err)
+	}
+	if app.Port != 5555 {
+		t.Errorf("Port = %d, want 5555 (env var must beat the config file)", app.Port)
+	}
+}
+
+func TestParseExecuteWithConfigFileBeatsStructDefault(t *testing.T) {
+	withArgs(t, "--config", writeConfigFile(t, 9090), "run")
+
+	app, err := ParseExecuteWithConfig[precedenceTestApp](nil, Options{})
+	if err != nil {
+		t.Fatalf("ParseExecuteWithConfig: %v", err)
+	}
+	if app.Port != 9090 {
+		t.Errorf("Port = %d, want 9090 (config file must beat the struct default)", app.Port)
+	}
+}
+
+func TestParseExecuteWithConfigStructDefaultWhenNothingElseSet(t *testing.T) {
+	withArgs(t, "run")
+
+	app, err := ParseExecuteWithConfig[precedenceTestApp](nil, Options{})
+	if err != nil {
+		t.Fatalf("ParseExecuteWithConfig: %v", err)
+	}
+	if app.Port != 1234 {
+		t.Errorf("Port = %d, want 1234 (struct default when nothing else is set)", app.Port)
+	}
+}
@@ -0,0 +1,261 @@
+// Package docgen generates man pages and Markdown reference docs for a CLI
+// app built with package cli, by introspecting its go-flags parser tree.
+package docgen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// Example is a single usage example rendered into both man pages and
+// Markdown, as returned by a command's ExamplesProvider.
+type Example struct {
+	Command     string
+	Description string
+}
+
+// ExamplesProvider is an optional interface for commands that want to ship
+// usage examples alongside their generated documentation.
+type ExamplesProvider interface {
+	Examples() []Example
+}
+
+// GenerateMan builds a go-flags parser around app and writes a roff(7) man
+// page for it, and one for each subcommand (recursively), into dir.
+func GenerateMan(app any, dir string) error {
+	return generate(app, dir, ".1", renderMan)
+}
+
+// GenerateMarkdown builds a go-flags parser around app and writes a
+// Markdown reference page for it, and one for each subcommand
+// (recursively), into dir.
+func GenerateMarkdown(app any, dir string) error {
+	return generate(app, dir, ".md", renderMarkdown)
+}
+
+type renderFunc func(root string, path []string, cmd *flags.Command, examples []Example) string
+
+func generate(app any, dir string, ext string, render renderFunc) error {
+	parser := flags.NewParser(app, flags.HelpFlag|flags.PassDoubleDash)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("docgen: creating %s: %w", dir, err)
+	}
+
+	return walk(parser.Command, nil, func(path []string, cmd *flags.Command) error {
+		name := parser.Name
+		if len(path) > 0 {
+			name += "-" + strings.Join(path, "-")
+		}
+
+		out := filepath.Join(dir, name+ext)
+		content := render(parser.Name, path, cmd, examplesFor(app, path))
+		if err := os.WriteFile(out, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("docgen: writing %s: %w", out, err)
+		}
+		return nil
+	})
+}
+
+// examplesFor locates the struct that go-flags registered as the subcommand
+// at path - found by walking app's own `command:"name"`-tagged fields in
+// lockstep with path, the same tag go-flags itself reads to build the
+// command tree - and returns its Examples, if it implements ExamplesProvider.
+//
+// go-flags does not expose the data a *flags.Command was registered with
+// once it is part of the parser tree, so this walks the original struct
+// directly rather than asking the parser for it.
+func examplesFor(app any, path []string) []Example {
+	data := dataAt(app, path)
+	if data == nil {
+		return nil
+	}
+	provider, ok := data.(ExamplesProvider)
+	if !ok {
+		return nil
+	}
+	return provider.Examples()
+}
+
+func dataAt(app any, path []string) any {
+	v := reflect.ValueOf(app)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	for _, name := range path {
+		next, ok := findCommandField(v, name)
+		if !ok {
+			return nil
+		}
+		v = next
+	}
+
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
+}
+
+// findCommandField looks, among v's fields, for the one tagged
+// `command:"name"` and returns the struct it points to.
+func findCommandField(v reflect.Value, name string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("command") != name {
+			continue
+		}
+
+		field := v.Field(i)
+		for field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				return reflect.Value{}, false
+			}
+			field = field.Elem()
+		}
+		return field, true
+	}
+	return reflect.Value{}, false
+}
+
+// walk invokes fn for cmd and every descendant command, depth-first, with
+// path holding the chain of subcommand names from the root to cmd.
+func walk(cmd *flags.Command, path []string, fn func(path []string, cmd *flags.Command) error) error {
+	if err := fn(path, cmd); err != nil {
+		return err
+	}
+
+	for _, sub := range cmd.Commands() {
+		if sub.Hidden {
+			continue
+		}
+		if err := walk(sub, append(append([]string{}, path...), sub.Name), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderMan(root string, path []string, cmd *flags.Command, examples []Example) string {
+	title := strings.ToUpper(strings.Join(append([]string{root}, path...), "-"))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1\n", title)
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", title, cmd.ShortDescription)
+
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n%s %s [OPTIONS]\n", root, strings.Join(path, " "))
+
+	if cmd.LongDescription != "" {
+		fmt.Fprintf(&b, ".SH DESCRIPTION\n%s\n", cmd.LongDescription)
+	}
+
+	if opts := allOptions(cmd); len(opts) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, opt := range opts {
+			fmt.Fprintf(&b, ".TP\n%s\n%s\n", optionHeading(opt), optionBody(opt))
+		}
+	}
+
+	if len(examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n")
+		for _, ex := range examples {
+			fmt.Fprintf(&b, ".TP\n%s\n%s\n", ex.Command, ex.Description)
+		}
+	}
+
+	return b.String()
+}
+
+func renderMarkdown(root string, path []string, cmd *flags.Command, examples []Example) string {
+	title := strings.Join(append([]string{root}, path...), " ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if cmd.ShortDescription != "" {
+		fmt.Fprintf(&b, "%s\n\n", cmd.ShortDescription)
+	}
+	if cmd.LongDescription != "" {
+		fmt.Fprintf(&b, "%s\n\n", cmd.LongDescription)
+	}
+
+	if opts := allOptions(cmd); len(opts) > 0 {
+		b.WriteString("## Options\n\n")
+		for _, opt := range opts {
+			fmt.Fprintf(&b, "- `%s` %s\n", optionHeading(opt), optionBody(opt))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(examples) > 0 {
+		b.WriteString("## Examples\n\n")
+		for _, ex := range examples {
+			fmt.Fprintf(&b, "```\n%s\n```\n\n%s\n\n", ex.Command, ex.Description)
+		}
+	}
+
+	if len(cmd.Commands()) > 0 {
+		b.WriteString("## Subcommands\n\n")
+		for _, sub := range cmd.Commands() {
+			if sub.Hidden {
+				continue
+			}
+			fmt.Fprintf(&b, "- `%s` - %s\n", sub.Name, sub.ShortDescription)
+		}
+	}
+
+	return b.String()
+}
+
+// allOptions returns every option belonging to cmd, including ones declared
+// in nested groups (e.g. the "Application Options" group go-flags creates
+// for a command's own top-level fields). cmd.Options() alone only sees
+// options added directly to cmd's own group, not its subgroups.
+func allOptions(cmd *flags.Command) []*flags.Option {
+	var out []*flags.Option
+	var walk func(g *flags.Group)
+	walk = func(g *flags.Group) {
+		out = append(out, g.Options()...)
+		for _, sub := range g.Groups() {
+			walk(sub)
+		}
+	}
+	walk(cmd.Group)
+	return out
+}
+
+func optionHeading(opt *flags.Option) string {
+	var names []string
+	if opt.ShortName != 0 {
+		names = append(names, "-"+string(opt.ShortName))
+	}
+	if opt.LongName != "" {
+		names = append(names, "--"+opt.LongName)
+	}
+	return strings.Join(names, ", ")
+}
+
+func optionBody(opt *flags.Option) string {
+	body := opt.Description
+	if len(opt.Choices) > 0 {
+		body += fmt.Sprintf(" (choices: %s)", strings.Join(opt.Choices, ", "))
+	}
+	if opt.EnvKeyWithNamespace() != "" {
+		body += fmt.Sprintf(" (env: %s)", opt.EnvKeyWithNamespace())
+	}
+	if opt.Required {
+		body += " (required)"
+	}
+	return body
+}
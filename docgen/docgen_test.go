@@ -0,0 +1,87 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type serveCmd struct {
+	Port int `long:"port" description:"port to listen on"`
+}
+
+func (c *serveCmd) Execute(args []string) error { return nil }
+
+func (c *serveCmd) Examples() []Example {
+	return []Example{{Command: "myapp serve --port 8080", Description: "Start the server on port 8080."}}
+}
+
+type docgenTestApp struct {
+	Verbose bool      `long:"verbose" short:"v" description:"enable verbose logging"`
+	Serve   *serveCmd `command:"serve" description:"run the server"`
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	app := &docgenTestApp{Serve: &serveCmd{}}
+
+	if err := GenerateMarkdown(app, dir); err != nil {
+		t.Fatalf("GenerateMarkdown: %v", err)
+	}
+
+	root, err := os.ReadFile(filepath.Join(dir, filepath.Base(os.Args[0])+".md"))
+	if err != nil {
+		t.Fatalf("reading root doc: %v", err)
+	}
+	if !strings.Contains(string(root), "--verbose") {
+		t.Errorf("root doc = %q, want it to mention --verbose", root)
+	}
+	if !strings.Contains(string(root), "serve") {
+		t.Errorf("root doc = %q, want it to list the serve subcommand", root)
+	}
+
+	serve, err := os.ReadFile(filepath.Join(dir, filepath.Base(os.Args[0])+"-serve.md"))
+	if err != nil {
+		t.Fatalf("reading serve doc: %v", err)
+	}
+	if !strings.Contains(string(serve), "--port") {
+		t.Errorf("serve doc = %q, want it to mention --port", serve)
+	}
+	if !strings.Contains(string(serve), "myapp serve --port 8080") {
+		t.Errorf("serve doc = %q, want it to include the command's Examples", serve)
+	}
+}
+
+func TestGenerateMan(t *testing.T) {
+	dir := t.TempDir()
+	app := &docgenTestApp{Serve: &serveCmd{}}
+
+	if err := GenerateMan(app, dir); err != nil {
+		t.Fatalf("GenerateMan: %v", err)
+	}
+
+	serve, err := os.ReadFile(filepath.Join(dir, filepath.Base(os.Args[0])+"-serve.1"))
+	if err != nil {
+		t.Fatalf("reading serve man page: %v", err)
+	}
+	if !strings.Contains(string(serve), ".SH EXAMPLES") {
+		t.Errorf("serve man page = %q, want an EXAMPLES section", serve)
+	}
+}
+
+func TestDataAt(t *testing.T) {
+	app := &docgenTestApp{Serve: &serveCmd{}}
+
+	data := dataAt(app, []string{"serve"})
+	if data == nil {
+		t.Fatal("dataAt(app, [serve]) = nil, want the *serveCmd")
+	}
+	if _, ok := data.(*serveCmd); !ok {
+		t.Fatalf("dataAt(app, [serve]) = %T, want *serveCmd", data)
+	}
+
+	if dataAt(app, []string{"missing"}) != nil {
+		t.Fatal("dataAt(app, [missing]) = non-nil, want nil")
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+// ErrorHandler processes an error produced while parsing flags or while
+// running a command's Validate/Init/Execute methods. It can transform the
+// error, perform side effects such as logging, or swallow it by returning
+// nil. ParseExecuteWith invokes it for every error instead of exiting the
+// process directly.
+type ErrorHandler interface {
+	HandleError(err error) error
+}
+
+// ErrorHandlerFunc adapts a plain function to ErrorHandler.
+type ErrorHandlerFunc func(err error) error
+
+func (f ErrorHandlerFunc) HandleError(err error) error {
+	return f(err)
+}
+
+// Options configures ParseExecuteWith.
+type Options struct {
+	// ErrorHandler runs on every error before it is returned to the caller.
+	// Defaults to a no-op handler that passes the error through unchanged.
+	ErrorHandler ErrorHandler
+}
+
+// ExitCoder is an optional interface for errors returned from a command's
+// Execute (or Validate/Init) that lets them request a specific process exit
+// code from the default ParseExecute error handler. Build one with Exit.
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+type exitError struct {
+	msg  string
+	code int
+}
+
+func (e *exitError) Error() string {
+	return e.msg
+}
+
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// Exit builds an error that, when handled by the default ParseExecute error
+// handler, prints msg and exits with code.
+func Exit(msg string, code int) error {
+	return &exitError{msg: msg, code: code}
+}
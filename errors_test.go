@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExitIsExitCoder(t *testing.T) {
+	err := Exit("boom", 7)
+
+	var exitCoder ExitCoder
+	if !errors.As(err, &exitCoder) {
+		t.Fatal("Exit(...) does not satisfy ExitCoder")
+	}
+	if exitCoder.ExitCode() != 7 {
+		t.Errorf("ExitCode() = %d, want 7", exitCoder.ExitCode())
+	}
+	if err.Error() != "boom" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "boom")
+	}
+}
+
+func TestErrorHandlerFunc(t *testing.T) {
+	var called error
+	handler := ErrorHandlerFunc(func(err error) error {
+		called = err
+		return nil
+	})
+
+	in := errors.New("bad input")
+	if err := handler.HandleError(in); err != nil {
+		t.Errorf("HandleError returned %v, want nil (swallowed)", err)
+	}
+	if called != in {
+		t.Errorf("handler was called with %v, want %v", called, in)
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ContextCommander is an optional interface for commands that want to
+// observe cancellation from the Timeout middleware. A command must still
+// implement Commander.Execute to satisfy go-flags' own registration
+// requirement; ExecuteContext is used instead of Execute whenever Timeout
+// wraps the chain and the active command implements it.
+type ContextCommander interface {
+	ExecuteContext(ctx context.Context, args []string) error
+}
+
+// Recover returns a Middleware that converts a panic in next (or anything it
+// wraps) into an error, so a single misbehaving command can't crash the
+// whole process.
+func Recover() Middleware {
+	return func(next CommandFunc) CommandFunc {
+		return func(cmd Commander, args []string) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("cli: recovered panic: %v", r)
+				}
+			}()
+			return next(cmd, args)
+		}
+	}
+}
+
+// funcCommander adapts a plain function to Commander, the way http.HandlerFunc
+// adapts a function to http.Handler.
+type funcCommander func(args []string) error
+
+func (f funcCommander) Execute(args []string) error { return f(args) }
+
+// Timeout returns a Middleware that gives up after d, returning an error
+// instead of waiting any longer for the command to finish. If the active
+// command implements ContextCommander, next is still called - so any
+// middleware between Timeout and the terminal dispatch still runs - but with
+// a stand-in Commander whose Execute calls ExecuteContext with a context
+// that is cancelled at the deadline; otherwise next runs as-is in the
+// background and is simply abandoned (not cancelled) once d elapses, so
+// Timeout should be placed as the innermost middleware to be effective for
+// plain Commander implementations. A panic in next is recovered and
+// returned as an error rather than crashing the process, same as Recover.
+func Timeout(d time.Duration) Middleware {
+	return func(next CommandFunc) CommandFunc {
+		return func(cmd Commander, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), d)
+			defer cancel()
+
+			dispatch := cmd
+			if ctxCmd, ok := cmd.(ContextCommander); ok {
+				dispatch = funcCommander(func(args []string) error {
+					return ctxCmd.ExecuteContext(ctx, args)
+				})
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- fmt.Errorf("cli: recovered panic: %v", r)
+					}
+				}()
+				done <- next(dispatch, args)
+			}()
+
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				return fmt.Errorf("cli: command timed out after %s", d)
+			}
+		}
+	}
+}
+
+// Trace returns a Middleware that logs a line before and after every
+// command dispatch, including its duration and any error, via logger.
+func Trace(logger *log.Logger) Middleware {
+	return func(next CommandFunc) CommandFunc {
+		return func(cmd Commander, args []string) error {
+			start := time.Now()
+			logger.Printf("cli: executing %T %v", cmd, args)
+
+			err := next(cmd, args)
+
+			logger.Printf("cli: executed %T in %s, err=%v", cmd, time.Since(start), err)
+			return err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type panicCmd struct{}
+
+func (c *panicCmd) Execute(_ []string) error {
+	panic("boom")
+}
+
+func TestRecoverConvertsPanicToError(t *testing.T) {
+	chain := Recover()(func(cmd Commander, args []string) error {
+		return cmd.Execute(args)
+	})
+
+	err := chain(&panicCmd{}, nil)
+	if err == nil {
+		t.Fatal("Recover: want an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Recover error = %q, want it to mention the panic value", err.Error())
+	}
+}
+
+type slowContextCmd struct {
+	delay time.Duration
+}
+
+func (c *slowContextCmd) Execute(_ []string) error { return nil }
+
+func (c *slowContextCmd) ExecuteContext(ctx context.Context, _ []string) error {
+	select {
+	case <-time.After(c.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestTimeoutExpiresContextCommander(t *testing.T) {
+	chain := Timeout(10 * time.Millisecond)(func(cmd Commander, args []string) error {
+		return cmd.Execute(args)
+	})
+
+	err := chain(&slowContextCmd{delay: 200 * time.Millisecond}, nil)
+	if err == nil {
+		t.Fatal("Timeout: want an error once the deadline passes, got nil")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Timeout error = %q, want it to mention the timeout", err.Error())
+	}
+}
+
+func TestTimeoutLetsFastContextCommanderFinish(t *testing.T) {
+	chain := Timeout(200 * time.Millisecond)(func(cmd Commander, args []string) error {
+		return cmd.Execute(args)
+	})
+
+	if err := chain(&slowContextCmd{delay: 10 * time.Millisecond}, nil); err != nil {
+		t.Fatalf("Timeout: want nil for a command finishing before the deadline, got %v", err)
+	}
+}
+
+type panicContextCmd struct{}
+
+func (c *panicContextCmd) Execute(_ []string) error { return nil }
+
+func (c *panicContextCmd) ExecuteContext(_ context.Context, _ []string) error {
+	panic("boom")
+}
+
+func TestRecoverTimeoutCatchesPanicInExecuteContext(t *testing.T) {
+	chain := Recover()(Timeout(200 * time.Millisecond)(func(cmd Commander, args []string) error {
+		return cmd.Execute(args)
+	}))
+
+	err := chain(&panicContextCmd{}, nil)
+	if err == nil {
+		t.Fatal("Recover+Timeout: want an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Recover+Timeout error = %q, want it to mention the panic value", err.Error())
+	}
+}
+
+func TestTimeoutCallsNextForContextCommander(t *testing.T) {
+	var sawDispatch bool
+	chain := Timeout(200 * time.Millisecond)(func(cmd Commander, args []string) error {
+		sawDispatch = true
+		return cmd.Execute(args)
+	})
+
+	if err := chain(&slowContextCmd{delay: 10 * time.Millisecond}, nil); err != nil {
+		t.Fatalf("Timeout: %v", err)
+	}
+	if !sawDispatch {
+		t.Fatal("Timeout: next was never invoked for a ContextCommander, middleware between Timeout and the terminal dispatch would be skipped")
+	}
+}
+
+func TestTimeoutIgnoresPlainCommander(t *testing.T) {
+	var executed bool
+	chain := Timeout(50 * time.Millisecond)(func(cmd Commander, args []string) error {
+		executed = true
+		return cmd.Execute(args)
+	})
+
+	if err := chain(&appTestCmd{}, nil); err != nil {
+		t.Fatalf("Timeout: want nil for a plain Commander, got %v", err)
+	}
+	if !executed {
+		t.Fatal("Timeout: next was never invoked for a plain Commander")
+	}
+}